@@ -0,0 +1,241 @@
+package kaitai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ParseCanceledError signals that a parse was aborted because its
+// context.Context was canceled or its deadline was exceeded. It carries
+// locationInfo so callers can see exactly where in the stream the parse was
+// when it was aborted.
+type ParseCanceledError struct {
+	locationInfo
+
+	err error
+}
+
+// NewParseCanceledError creates a new ParseCanceledError instance wrapping
+// ctx.Err().
+func NewParseCanceledError(err error, io *Stream, srcPath string) ParseCanceledError {
+	return ParseCanceledError{
+		newLocationInfo(io, srcPath),
+		err,
+	}
+}
+
+// Unwrap returns ctx.Err(), so that errors.Is(err, context.Canceled) and
+// errors.Is(err, context.DeadlineExceeded) work as expected.
+func (e ParseCanceledError) Unwrap() error { return e.err }
+
+func (e ParseCanceledError) Error() string {
+	return e.msgWithLocation(fmt.Sprintf("parse canceled: %s", e.err))
+}
+
+// ErrorKind returns the stable, machine-readable identifier for this error,
+// suitable for JSON serialization and use by external tooling.
+func (ParseCanceledError) ErrorKind() string { return "parse_canceled" }
+
+// MarshalJSON implements json.Marshaler, emitting a canonical JSON
+// representation of the error for tooling that wraps Kaitai-generated
+// parsers (linters, dissectors, fuzzers, CI reporters).
+func (e ParseCanceledError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonError{
+		Kind:    e.ErrorKind(),
+		SrcPath: e.SrcPath(),
+		Pos:     posOf(e.Io()),
+		Message: e.err.Error(),
+	})
+}
+
+// checkContext returns a *ParseCanceledError if ctx has been canceled or its
+// deadline has been exceeded, and nil otherwise. The CtxStream *Ctx read
+// methods call this before each read, so a long-running parse of a huge or
+// lazily streamed binary stops at the next field once ctx is done.
+func checkContext(ctx context.Context, io *Stream, srcPath string) error {
+	select {
+	case <-ctx.Done():
+		err := NewParseCanceledError(ctx.Err(), io, srcPath)
+		return &err
+	default:
+		return nil
+	}
+}
+
+// CtxStream wraps a *Stream with a context.Context, so that the *Ctx read
+// methods below can check for cancellation before doing any work. Obtain one
+// via Stream.WithContext.
+type CtxStream struct {
+	*Stream
+	ctx context.Context
+}
+
+// WithContext returns a CtxStream backed by s whose *Ctx methods refuse to
+// start a read once ctx is canceled or its deadline is exceeded; a read that
+// has already started is not interrupted. Each *Ctx method
+// below takes its own srcPath, the same way NewValidationXError and
+// RunValidator do, so a ParseCanceledError raised partway through a struct
+// always names the specific field being read rather than whatever field was
+// current when WithContext was called.
+func (s *Stream) WithContext(ctx context.Context) *CtxStream {
+	return &CtxStream{Stream: s, ctx: ctx}
+}
+
+func (cs *CtxStream) checkContext(srcPath string) error {
+	return checkContext(cs.ctx, cs.Stream, srcPath)
+}
+
+// ReadU1Ctx is the context-aware variant of Stream.ReadU1: it returns a
+// ParseCanceledError instead of reading if cs's context has already been
+// canceled or its deadline exceeded.
+func (cs *CtxStream) ReadU1Ctx(srcPath string) (uint8, error) {
+	if err := cs.checkContext(srcPath); err != nil {
+		return 0, err
+	}
+	return cs.ReadU1()
+}
+
+// ReadU2leCtx is the context-aware variant of Stream.ReadU2le.
+func (cs *CtxStream) ReadU2leCtx(srcPath string) (uint16, error) {
+	if err := cs.checkContext(srcPath); err != nil {
+		return 0, err
+	}
+	return cs.ReadU2le()
+}
+
+// ReadU2beCtx is the context-aware variant of Stream.ReadU2be.
+func (cs *CtxStream) ReadU2beCtx(srcPath string) (uint16, error) {
+	if err := cs.checkContext(srcPath); err != nil {
+		return 0, err
+	}
+	return cs.ReadU2be()
+}
+
+// ReadU4leCtx is the context-aware variant of Stream.ReadU4le.
+func (cs *CtxStream) ReadU4leCtx(srcPath string) (uint32, error) {
+	if err := cs.checkContext(srcPath); err != nil {
+		return 0, err
+	}
+	return cs.ReadU4le()
+}
+
+// ReadU4beCtx is the context-aware variant of Stream.ReadU4be.
+func (cs *CtxStream) ReadU4beCtx(srcPath string) (uint32, error) {
+	if err := cs.checkContext(srcPath); err != nil {
+		return 0, err
+	}
+	return cs.ReadU4be()
+}
+
+// ReadU8leCtx is the context-aware variant of Stream.ReadU8le.
+func (cs *CtxStream) ReadU8leCtx(srcPath string) (uint64, error) {
+	if err := cs.checkContext(srcPath); err != nil {
+		return 0, err
+	}
+	return cs.ReadU8le()
+}
+
+// ReadU8beCtx is the context-aware variant of Stream.ReadU8be.
+func (cs *CtxStream) ReadU8beCtx(srcPath string) (uint64, error) {
+	if err := cs.checkContext(srcPath); err != nil {
+		return 0, err
+	}
+	return cs.ReadU8be()
+}
+
+// ReadS1Ctx is the context-aware variant of Stream.ReadS1.
+func (cs *CtxStream) ReadS1Ctx(srcPath string) (int8, error) {
+	if err := cs.checkContext(srcPath); err != nil {
+		return 0, err
+	}
+	return cs.ReadS1()
+}
+
+// ReadS2leCtx is the context-aware variant of Stream.ReadS2le.
+func (cs *CtxStream) ReadS2leCtx(srcPath string) (int16, error) {
+	if err := cs.checkContext(srcPath); err != nil {
+		return 0, err
+	}
+	return cs.ReadS2le()
+}
+
+// ReadS2beCtx is the context-aware variant of Stream.ReadS2be.
+func (cs *CtxStream) ReadS2beCtx(srcPath string) (int16, error) {
+	if err := cs.checkContext(srcPath); err != nil {
+		return 0, err
+	}
+	return cs.ReadS2be()
+}
+
+// ReadS4leCtx is the context-aware variant of Stream.ReadS4le.
+func (cs *CtxStream) ReadS4leCtx(srcPath string) (int32, error) {
+	if err := cs.checkContext(srcPath); err != nil {
+		return 0, err
+	}
+	return cs.ReadS4le()
+}
+
+// ReadS4beCtx is the context-aware variant of Stream.ReadS4be.
+func (cs *CtxStream) ReadS4beCtx(srcPath string) (int32, error) {
+	if err := cs.checkContext(srcPath); err != nil {
+		return 0, err
+	}
+	return cs.ReadS4be()
+}
+
+// ReadS8leCtx is the context-aware variant of Stream.ReadS8le.
+func (cs *CtxStream) ReadS8leCtx(srcPath string) (int64, error) {
+	if err := cs.checkContext(srcPath); err != nil {
+		return 0, err
+	}
+	return cs.ReadS8le()
+}
+
+// ReadS8beCtx is the context-aware variant of Stream.ReadS8be.
+func (cs *CtxStream) ReadS8beCtx(srcPath string) (int64, error) {
+	if err := cs.checkContext(srcPath); err != nil {
+		return 0, err
+	}
+	return cs.ReadS8be()
+}
+
+// ReadF4leCtx is the context-aware variant of Stream.ReadF4le.
+func (cs *CtxStream) ReadF4leCtx(srcPath string) (float32, error) {
+	if err := cs.checkContext(srcPath); err != nil {
+		return 0, err
+	}
+	return cs.ReadF4le()
+}
+
+// ReadF4beCtx is the context-aware variant of Stream.ReadF4be.
+func (cs *CtxStream) ReadF4beCtx(srcPath string) (float32, error) {
+	if err := cs.checkContext(srcPath); err != nil {
+		return 0, err
+	}
+	return cs.ReadF4be()
+}
+
+// ReadF8leCtx is the context-aware variant of Stream.ReadF8le.
+func (cs *CtxStream) ReadF8leCtx(srcPath string) (float64, error) {
+	if err := cs.checkContext(srcPath); err != nil {
+		return 0, err
+	}
+	return cs.ReadF8le()
+}
+
+// ReadF8beCtx is the context-aware variant of Stream.ReadF8be.
+func (cs *CtxStream) ReadF8beCtx(srcPath string) (float64, error) {
+	if err := cs.checkContext(srcPath); err != nil {
+		return 0, err
+	}
+	return cs.ReadF8be()
+}
+
+// ReadBytesCtx is the context-aware variant of Stream.ReadBytes.
+func (cs *CtxStream) ReadBytesCtx(srcPath string, n int) ([]byte, error) {
+	if err := cs.checkContext(srcPath); err != nil {
+		return nil, err
+	}
+	return cs.ReadBytes(n)
+}
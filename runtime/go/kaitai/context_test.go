@@ -0,0 +1,84 @@
+package kaitai
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCheckContextReturnsNilWhenNotCanceled(t *testing.T) {
+	if err := checkContext(context.Background(), nil, "field_a"); err != nil {
+		t.Errorf("expected nil for a live context, got %v", err)
+	}
+}
+
+func TestCheckContextReturnsParseCanceledErrorWhenCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := checkContext(ctx, nil, "field_b")
+	if err == nil {
+		t.Fatal("expected an error for a canceled context")
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected errors.Is to match context.Canceled, got %v", err)
+	}
+
+	var parseCanceled *ParseCanceledError
+	if !errors.As(err, &parseCanceled) {
+		t.Fatalf("expected a *ParseCanceledError, got %T", err)
+	}
+	if parseCanceled.SrcPath() != "field_b" {
+		t.Errorf(`expected src_path "field_b", got %q`, parseCanceled.SrcPath())
+	}
+	if parseCanceled.ErrorKind() != "parse_canceled" {
+		t.Errorf(`expected ErrorKind "parse_canceled", got %q`, parseCanceled.ErrorKind())
+	}
+}
+
+// TestCheckContextUsesThePerCallSrcPath guards against the regression fixed
+// in ed7e1ff, where srcPath was bound once on CtxStream.WithContext instead
+// of being threaded through each *Ctx call — a ParseCanceledError raised on
+// a later field would wrongly report an earlier field's srcPath.
+func TestCheckContextUsesThePerCallSrcPath(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	errA := checkContext(ctx, nil, "field_a")
+	errB := checkContext(ctx, nil, "field_b")
+
+	var canceledA, canceledB *ParseCanceledError
+	if !errors.As(errA, &canceledA) || !errors.As(errB, &canceledB) {
+		t.Fatal("expected both calls to produce a *ParseCanceledError")
+	}
+	if canceledA.SrcPath() != "field_a" || canceledB.SrcPath() != "field_b" {
+		t.Errorf("expected each call to keep its own srcPath, got %q and %q",
+			canceledA.SrcPath(), canceledB.SrcPath())
+	}
+}
+
+func TestParseCanceledErrorMarshalJSON(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := checkContext(ctx, nil, "field_c")
+	var parseCanceled *ParseCanceledError
+	if !errors.As(err, &parseCanceled) {
+		t.Fatalf("expected a *ParseCanceledError, got %T", err)
+	}
+
+	raw, marshalErr := parseCanceled.MarshalJSON()
+	if marshalErr != nil {
+		t.Fatalf("unexpected error: %v", marshalErr)
+	}
+
+	// ErrorsToJSON must recognize ParseCanceledError as a json.Marshaler
+	// instead of falling back to the generic "unknown" kind.
+	rawFromHelper, err2 := ErrorsToJSON([]error{*parseCanceled})
+	if err2 != nil {
+		t.Fatalf("unexpected error: %v", err2)
+	}
+	if string(rawFromHelper) != "["+string(raw)+"]" {
+		t.Errorf("expected ErrorsToJSON to use ParseCanceledError.MarshalJSON directly, got %s", rawFromHelper)
+	}
+}
@@ -2,13 +2,29 @@ package kaitai
 
 import "fmt"
 
-// EndOfStreamError is returned when the stream unexpectedly ends.
-type EndOfStreamError struct{}
+// EndOfStreamError is returned when the stream unexpectedly ends. Its zero
+// value, EndOfStreamError{}, remains valid for existing call sites that
+// don't have a *Stream/srcPath on hand; NewEndOfStreamError additionally
+// attaches locationInfo so the error's JSON form can carry "src_path"/"pos"
+// like the Validation*Errors do.
+type EndOfStreamError struct {
+	locationInfo
+}
+
+// NewEndOfStreamError creates a new EndOfStreamError instance with location
+// info attached.
+func NewEndOfStreamError(io *Stream, srcPath string) EndOfStreamError {
+	return EndOfStreamError{newLocationInfo(io, srcPath)}
+}
 
-func (EndOfStreamError) Error() string {
-	return "unexpected end of stream"
+func (e EndOfStreamError) Error() string {
+	return e.msgWithLocation("unexpected end of stream")
 }
 
+// ErrorKind returns the stable, machine-readable identifier for this error,
+// suitable for JSON serialization and use by external tooling.
+func (EndOfStreamError) ErrorKind() string { return "end_of_stream" }
+
 // UndecidedEndiannessError occurs when a value has calculated or inherited
 // endianness, and the endianness could not be determined.
 type UndecidedEndiannessError struct{}
@@ -34,19 +50,26 @@ func (l locationInfo) Io() *Stream { return l.io }
 func (l locationInfo) SrcPath() string { return l.srcPath }
 
 func (l locationInfo) msgWithLocation(msg string) string {
-	var pos any
-	pos, err := l.io.Pos()
-	if err != nil {
-		pos = "N/A"
+	pos := any("N/A")
+	if l.io != nil {
+		if p, err := l.io.Pos(); err == nil {
+			pos = p
+		}
+	}
+	if uri, ok := sourceURIOf(l.io); ok {
+		return fmt.Sprintf("%s (%s): at pos %v: %s", l.srcPath, uri, pos, msg)
 	}
 	return fmt.Sprintf("%s: at pos %v: %s", l.srcPath, pos, msg)
 }
 
 // ValidationFailedError is an interface that all "Validation*Error"s implement.
 type ValidationFailedError interface {
+	error
+
 	Actual() any
 	Io() *Stream
 	SrcPath() string
+	ErrorKind() string
 }
 
 func validationFailedMsg(msg string) string {
@@ -86,6 +109,10 @@ func (e ValidationNotEqualError) Error() string {
 	)
 }
 
+// ErrorKind returns the stable, machine-readable identifier for this error,
+// suitable for JSON serialization and use by external tooling.
+func (ValidationNotEqualError) ErrorKind() string { return "not_equal" }
+
 // ValidationLessThanError signals validation failure: we required "Actual" value
 // to be greater than or equal to "Min", but it turned out that it's not.
 type ValidationLessThanError struct {
@@ -119,6 +146,10 @@ func (e ValidationLessThanError) Error() string {
 	)
 }
 
+// ErrorKind returns the stable, machine-readable identifier for this error,
+// suitable for JSON serialization and use by external tooling.
+func (ValidationLessThanError) ErrorKind() string { return "less_than" }
+
 // ValidationGreaterThanError signals validation failure: we required "Actual" value
 // to be less than or equal to "Max", but it turned out that it's not.
 type ValidationGreaterThanError struct {
@@ -152,6 +183,10 @@ func (e ValidationGreaterThanError) Error() string {
 	)
 }
 
+// ErrorKind returns the stable, machine-readable identifier for this error,
+// suitable for JSON serialization and use by external tooling.
+func (ValidationGreaterThanError) ErrorKind() string { return "greater_than" }
+
 // ValidationNotAnyOfError signals validation failure: we required "Actual" value
 // to be from the list, but it turned out that it's not.
 type ValidationNotAnyOfError struct {
@@ -179,6 +214,10 @@ func (e ValidationNotAnyOfError) Error() string {
 	)
 }
 
+// ErrorKind returns the stable, machine-readable identifier for this error,
+// suitable for JSON serialization and use by external tooling.
+func (ValidationNotAnyOfError) ErrorKind() string { return "not_any_of" }
+
 // ValidationNotInEnumError signals validation failure: we required "Actual" value
 // to be in the enum, but it turned out that it's not.
 type ValidationNotInEnumError struct {
@@ -206,6 +245,10 @@ func (e ValidationNotInEnumError) Error() string {
 	)
 }
 
+// ErrorKind returns the stable, machine-readable identifier for this error,
+// suitable for JSON serialization and use by external tooling.
+func (ValidationNotInEnumError) ErrorKind() string { return "not_in_enum" }
+
 // ValidationExprError signals validation failure: we required "Actual" value
 // to match the expression, but it turned out that it doesn't.
 type ValidationExprError struct {
@@ -232,3 +275,7 @@ func (e ValidationExprError) Error() string {
 		),
 	)
 }
+
+// ErrorKind returns the stable, machine-readable identifier for this error,
+// suitable for JSON serialization and use by external tooling.
+func (ValidationExprError) ErrorKind() string { return "expr" }
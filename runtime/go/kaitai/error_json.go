@@ -0,0 +1,142 @@
+package kaitai
+
+import "encoding/json"
+
+// jsonError is the stable, machine-readable representation shared by all
+// error kinds. Fields that don't apply to a given error kind (e.g. "expected"
+// for ValidationNotAnyOfError) are simply omitted.
+type jsonError struct {
+	Kind     string `json:"kind"`
+	SrcPath  string `json:"src_path,omitempty"`
+	Pos      any    `json:"pos,omitempty"`
+	Expected any    `json:"expected,omitempty"`
+	Min      any    `json:"min,omitempty"`
+	Max      any    `json:"max,omitempty"`
+	Actual   any    `json:"actual,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+func posOf(io *Stream) any {
+	if io == nil {
+		return nil
+	}
+	pos, err := io.Pos()
+	if err != nil {
+		return nil
+	}
+	return pos
+}
+
+// MarshalJSON implements json.Marshaler, emitting a canonical JSON
+// representation of the error for tooling that wraps Kaitai-generated
+// parsers (linters, dissectors, fuzzers, CI reporters). "src_path"/"pos" are
+// only present when the error was created via NewEndOfStreamError; the bare
+// EndOfStreamError{} zero value used at older call sites has no location to
+// report.
+func (e EndOfStreamError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonError{
+		Kind:    e.ErrorKind(),
+		SrcPath: e.SrcPath(),
+		Pos:     posOf(e.Io()),
+	})
+}
+
+// MarshalJSON implements json.Marshaler, emitting a canonical JSON
+// representation of the error for tooling that wraps Kaitai-generated
+// parsers (linters, dissectors, fuzzers, CI reporters).
+func (e ValidationNotEqualError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonError{
+		Kind:     e.ErrorKind(),
+		SrcPath:  e.SrcPath(),
+		Pos:      posOf(e.Io()),
+		Expected: e.expected,
+		Actual:   e.actual,
+	})
+}
+
+// MarshalJSON implements json.Marshaler, emitting a canonical JSON
+// representation of the error for tooling that wraps Kaitai-generated
+// parsers (linters, dissectors, fuzzers, CI reporters).
+func (e ValidationLessThanError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonError{
+		Kind:    e.ErrorKind(),
+		SrcPath: e.SrcPath(),
+		Pos:     posOf(e.Io()),
+		Min:     e.min,
+		Actual:  e.actual,
+	})
+}
+
+// MarshalJSON implements json.Marshaler, emitting a canonical JSON
+// representation of the error for tooling that wraps Kaitai-generated
+// parsers (linters, dissectors, fuzzers, CI reporters).
+func (e ValidationGreaterThanError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonError{
+		Kind:    e.ErrorKind(),
+		SrcPath: e.SrcPath(),
+		Pos:     posOf(e.Io()),
+		Max:     e.max,
+		Actual:  e.actual,
+	})
+}
+
+// MarshalJSON implements json.Marshaler, emitting a canonical JSON
+// representation of the error for tooling that wraps Kaitai-generated
+// parsers (linters, dissectors, fuzzers, CI reporters).
+func (e ValidationNotAnyOfError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonError{
+		Kind:    e.ErrorKind(),
+		SrcPath: e.SrcPath(),
+		Pos:     posOf(e.Io()),
+		Actual:  e.actual,
+	})
+}
+
+// MarshalJSON implements json.Marshaler, emitting a canonical JSON
+// representation of the error for tooling that wraps Kaitai-generated
+// parsers (linters, dissectors, fuzzers, CI reporters).
+func (e ValidationNotInEnumError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonError{
+		Kind:    e.ErrorKind(),
+		SrcPath: e.SrcPath(),
+		Pos:     posOf(e.Io()),
+		Actual:  e.actual,
+	})
+}
+
+// MarshalJSON implements json.Marshaler, emitting a canonical JSON
+// representation of the error for tooling that wraps Kaitai-generated
+// parsers (linters, dissectors, fuzzers, CI reporters).
+func (e ValidationExprError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonError{
+		Kind:    e.ErrorKind(),
+		SrcPath: e.SrcPath(),
+		Pos:     posOf(e.Io()),
+		Actual:  e.actual,
+	})
+}
+
+// ErrorsToJSON renders a slice of errors as a JSON array, using each error's
+// own json.Marshaler implementation when available and falling back to
+// {"kind":"unknown","message":"..."} for errors outside the kaitai error
+// family (e.g. ones returned by a user-supplied io.Reader).
+func ErrorsToJSON(errs []error) ([]byte, error) {
+	rendered := make([]json.RawMessage, len(errs))
+	for i, err := range errs {
+		if marshaler, ok := err.(json.Marshaler); ok {
+			raw, marshalErr := marshaler.MarshalJSON()
+			if marshalErr != nil {
+				return nil, marshalErr
+			}
+			rendered[i] = raw
+			continue
+		}
+
+		raw, marshalErr := json.Marshal(jsonError{Kind: "unknown", Message: err.Error()})
+		if marshalErr != nil {
+			return nil, marshalErr
+		}
+		rendered[i] = raw
+	}
+	return json.Marshal(rendered)
+}
@@ -0,0 +1,110 @@
+package kaitai
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEndOfStreamErrorMarshalJSON(t *testing.T) {
+	raw, err := EndOfStreamError{}.MarshalJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("unexpected error unmarshaling: %v", err)
+	}
+	if decoded["kind"] != "end_of_stream" {
+		t.Errorf(`expected kind "end_of_stream", got %v`, decoded["kind"])
+	}
+	if _, ok := decoded["src_path"]; ok {
+		t.Error("expected no src_path for a location-less EndOfStreamError")
+	}
+}
+
+func TestValidationNotEqualErrorMarshalJSON(t *testing.T) {
+	e := NewValidationNotEqualError(42, 7, nil, "field_a")
+
+	raw, err := e.MarshalJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("unexpected error unmarshaling: %v", err)
+	}
+	if decoded["kind"] != "not_equal" {
+		t.Errorf(`expected kind "not_equal", got %v`, decoded["kind"])
+	}
+	if decoded["src_path"] != "field_a" {
+		t.Errorf(`expected src_path "field_a", got %v`, decoded["src_path"])
+	}
+	if decoded["expected"] != float64(42) {
+		t.Errorf("expected expected=42, got %v", decoded["expected"])
+	}
+	if decoded["actual"] != float64(7) {
+		t.Errorf("expected actual=7, got %v", decoded["actual"])
+	}
+}
+
+// TestMarshalJSONZeroActualSurvivesOmitempty guards against the "actual"/
+// "expected" fields being dropped by `omitempty` whenever the failing value
+// happens to be a zero value (0, false, ""). Since those struct fields are
+// typed `any`, encoding/json's omitempty only triggers on a nil interface,
+// not on a zero underlying value, so a zero Actual must still appear in the
+// stable schema.
+func TestMarshalJSONZeroActualSurvivesOmitempty(t *testing.T) {
+	e := NewValidationNotAnyOfError(0, nil, "field_b")
+
+	raw, err := e.MarshalJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("unexpected error unmarshaling: %v", err)
+	}
+	actual, ok := decoded["actual"]
+	if !ok {
+		t.Fatal(`expected "actual" key to be present even though the value is the zero value 0`)
+	}
+	if actual != float64(0) {
+		t.Errorf("expected actual=0, got %v", actual)
+	}
+}
+
+func TestErrorsToJSONFallsBackForNonKaitaiErrors(t *testing.T) {
+	errs := []error{
+		NewValidationExprError(5, nil, "field_c"),
+		errFromPlainGo("boom"),
+	}
+
+	raw, err := ErrorsToJSON(errs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded []map[string]any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("unexpected error unmarshaling: %v", err)
+	}
+	if len(decoded) != 2 {
+		t.Fatalf("expected 2 rendered errors, got %d", len(decoded))
+	}
+	if decoded[0]["kind"] != "expr" {
+		t.Errorf(`expected first error kind "expr", got %v`, decoded[0]["kind"])
+	}
+	if decoded[1]["kind"] != "unknown" {
+		t.Errorf(`expected second error kind "unknown", got %v`, decoded[1]["kind"])
+	}
+	if decoded[1]["message"] != "boom" {
+		t.Errorf(`expected second error message "boom", got %v`, decoded[1]["message"])
+	}
+}
+
+type errFromPlainGo string
+
+func (e errFromPlainGo) Error() string { return string(e) }
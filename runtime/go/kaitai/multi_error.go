@@ -0,0 +1,77 @@
+package kaitai
+
+import "strings"
+
+// ParseOptions controls optional behavior of generated Read* methods, such
+// as attaching a ValidationCollector to run in collect-all mode instead of
+// the default fail-fast mode.
+type ParseOptions struct {
+	// Collector, when non-nil, causes validation failures to be appended to
+	// it and parsing to continue instead of returning on the first error.
+	Collector *ValidationCollector
+}
+
+// ValidationCollector accumulates ValidationFailedError instances encountered
+// while parsing, instead of having the first one abort the parse. Attach one
+// via ParseOptions to a generated Read* method to switch it from fail-fast to
+// collect-all mode. The zero value is ready to use.
+type ValidationCollector struct {
+	errs []ValidationFailedError
+}
+
+// Collect appends err to the collector. Generated code calls this in place
+// of returning err directly when a collector is attached.
+func (c *ValidationCollector) Collect(err ValidationFailedError) {
+	c.errs = append(c.errs, err)
+}
+
+// Errors returns the validation errors collected so far, in the order they
+// were encountered.
+func (c *ValidationCollector) Errors() []ValidationFailedError {
+	return c.errs
+}
+
+// HasErrors reports whether any validation errors have been collected.
+func (c *ValidationCollector) HasErrors() bool {
+	return len(c.errs) > 0
+}
+
+// Err returns a *MultiValidationError wrapping all collected errors, or nil
+// if none were collected.
+func (c *ValidationCollector) Err() error {
+	if !c.HasErrors() {
+		return nil
+	}
+	return &MultiValidationError{errs: c.errs}
+}
+
+// MultiValidationError aggregates every ValidationFailedError encountered
+// while parsing in collect-all mode, so callers can see every violation in a
+// malformed binary at once instead of fixing them one at a time.
+type MultiValidationError struct {
+	errs []ValidationFailedError
+}
+
+// Errors returns the aggregated validation errors, in the order they were
+// encountered.
+func (e *MultiValidationError) Errors() []ValidationFailedError {
+	return e.errs
+}
+
+// Unwrap returns the aggregated errors so that errors.Is and errors.As can
+// match against any one of them.
+func (e *MultiValidationError) Unwrap() []error {
+	unwrapped := make([]error, len(e.errs))
+	for i, err := range e.errs {
+		unwrapped[i] = err
+	}
+	return unwrapped
+}
+
+func (e *MultiValidationError) Error() string {
+	msgs := make([]string, len(e.errs))
+	for i, err := range e.errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
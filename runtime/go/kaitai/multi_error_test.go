@@ -0,0 +1,54 @@
+package kaitai
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidationCollectorAggregatesAndUnwraps(t *testing.T) {
+	var collector ValidationCollector
+
+	err1 := NewValidationNotEqualError(42, 7, nil, "field_a")
+	err2 := NewValidationLessThanError(10, 3, nil, "field_b")
+
+	collector.Collect(err1)
+	collector.Collect(err2)
+
+	if !collector.HasErrors() {
+		t.Fatal("expected collector to report having errors")
+	}
+
+	multi, ok := collector.Err().(*MultiValidationError)
+	if !ok {
+		t.Fatalf("expected *MultiValidationError, got %T", collector.Err())
+	}
+	if len(multi.Errors()) != 2 {
+		t.Fatalf("expected 2 aggregated errors, got %d", len(multi.Errors()))
+	}
+
+	if !errors.Is(multi, err1) {
+		t.Error("errors.Is should match the first collected error")
+	}
+	if !errors.Is(multi, err2) {
+		t.Error("errors.Is should match the second collected error")
+	}
+
+	var asNotEqual ValidationNotEqualError
+	if !errors.As(multi, &asNotEqual) {
+		t.Error("errors.As should find the ValidationNotEqualError in the chain")
+	}
+
+	// Errors built with a nil *Stream (e.g. from a custom validator with no
+	// live stream on hand) must still render instead of panicking, since
+	// MultiValidationError.Error() calls Error() on every collected error.
+	if got := multi.Error(); got == "" {
+		t.Error("expected a non-empty rendering of the aggregated errors")
+	}
+}
+
+func TestValidationCollectorErrNilWhenEmpty(t *testing.T) {
+	var collector ValidationCollector
+	if err := collector.Err(); err != nil {
+		t.Errorf("expected nil error from an empty collector, got %v", err)
+	}
+}
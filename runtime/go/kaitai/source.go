@@ -0,0 +1,310 @@
+package kaitai
+
+import (
+	"container/list"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// Source is the underlying random-access data source behind a Stream. It
+// generalizes the historical "read from a local *os.File or []byte"
+// assumption so that large archived binaries (firmware dumps, disk images,
+// capture files) stored in object storage can be parsed via ranged reads
+// instead of having to be downloaded in full first.
+type Source interface {
+	io.ReaderAt
+
+	// Size returns the total size of the source in bytes.
+	Size() (int64, error)
+
+	// URI identifies the source for diagnostics, e.g. "file:///a/b.bin" or
+	// "s3://bucket/key". Returns "" if the source has no meaningful URI.
+	URI() string
+}
+
+// sourceURIRegistry maps a *Stream constructed via NewStreamFromSource back
+// to its Source's URI, so that locationInfo.msgWithLocation can mention it
+// without Stream itself needing a field for it. It is keyed by the Stream's
+// address rather than the pointer itself, so the registry does not keep the
+// Stream reachable. Entries are removed by a
+// finalizer registered in NewStreamFromSource once the Stream becomes
+// unreachable, so parsing many remote objects over a long-running process
+// doesn't leak a registry entry (and the Source/blockCache it keeps alive)
+// per Stream forever.
+var (
+	sourceURIRegistryMu sync.Mutex
+	sourceURIRegistry   = map[uintptr]string{}
+)
+
+func sourceURIOf(s *Stream) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+	sourceURIRegistryMu.Lock()
+	defer sourceURIRegistryMu.Unlock()
+	uri, ok := sourceURIRegistry[uintptr(unsafe.Pointer(s))]
+	return uri, ok && uri != ""
+}
+
+// FileSource adapts an *os.File to Source.
+type FileSource struct {
+	f *os.File
+}
+
+// NewFileSource creates a FileSource backed by f.
+func NewFileSource(f *os.File) *FileSource { return &FileSource{f} }
+
+// ReadAt implements io.ReaderAt.
+func (s *FileSource) ReadAt(p []byte, off int64) (int, error) { return s.f.ReadAt(p, off) }
+
+// Size implements Source.
+func (s *FileSource) Size() (int64, error) {
+	info, err := s.f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// URI implements Source.
+func (s *FileSource) URI() string { return "file://" + s.f.Name() }
+
+// BytesSource adapts an in-memory byte slice to Source.
+type BytesSource struct {
+	b []byte
+}
+
+// NewBytesSource creates a BytesSource backed by b.
+func NewBytesSource(b []byte) *BytesSource { return &BytesSource{b} }
+
+// ReadAt implements io.ReaderAt.
+func (s *BytesSource) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 || off > int64(len(s.b)) {
+		return 0, fmt.Errorf("kaitai: BytesSource.ReadAt: offset %d out of range", off)
+	}
+	n := copy(p, s.b[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Size implements Source.
+func (s *BytesSource) Size() (int64, error) { return int64(len(s.b)), nil }
+
+// URI implements Source.
+func (s *BytesSource) URI() string { return "" }
+
+// RangeFetcher performs a single ranged read against a remote object,
+// returning exactly the bytes in [off, off+len(p)) (or fewer at EOF). It is
+// the low-level primitive behind HTTPRangeSource and ObjectStoreSource.
+type RangeFetcher interface {
+	FetchRange(p []byte, off int64) (int, error)
+	Size() (int64, error)
+}
+
+// HTTPRangeSource adapts a remote resource fetched via HTTP Range requests
+// to Source. fetcher performs the actual requests; callers typically supply
+// one backed by net/http with an "Accept-Ranges: bytes"-capable server.
+type HTTPRangeSource struct {
+	uri     string
+	fetcher RangeFetcher
+	cache   *blockCache
+}
+
+// NewHTTPRangeSource creates an HTTPRangeSource for uri, reading through
+// fetcher. Ranged reads are served out of an LRU block cache of the given
+// block size (bytes) and block count, so repeated Pos()/Seek()-heavy access
+// patterns common to Kaitai parsers don't re-fetch the same bytes.
+func NewHTTPRangeSource(uri string, fetcher RangeFetcher, blockSize int, cacheBlocks int) *HTTPRangeSource {
+	return &HTTPRangeSource{
+		uri:     uri,
+		fetcher: fetcher,
+		cache:   newBlockCache(fetcher.FetchRange, blockSize, cacheBlocks),
+	}
+}
+
+// ReadAt implements io.ReaderAt.
+func (s *HTTPRangeSource) ReadAt(p []byte, off int64) (int, error) { return s.cache.ReadAt(p, off) }
+
+// Size implements Source.
+func (s *HTTPRangeSource) Size() (int64, error) { return s.fetcher.Size() }
+
+// URI implements Source.
+func (s *HTTPRangeSource) URI() string { return s.uri }
+
+// ObjectStoreSource adapts an object in an S3/MinIO/GCS-compatible object
+// store to Source, using the same RangeFetcher abstraction as
+// HTTPRangeSource (each of those SDKs already expose a ranged GetObject
+// call that RangeFetcher wraps).
+type ObjectStoreSource struct {
+	uri     string
+	fetcher RangeFetcher
+	cache   *blockCache
+}
+
+// NewObjectStoreSource creates an ObjectStoreSource identified by uri (e.g.
+// "s3://bucket/key"), reading through fetcher and caching ranged reads the
+// same way HTTPRangeSource does.
+func NewObjectStoreSource(uri string, fetcher RangeFetcher, blockSize int, cacheBlocks int) *ObjectStoreSource {
+	return &ObjectStoreSource{
+		uri:     uri,
+		fetcher: fetcher,
+		cache:   newBlockCache(fetcher.FetchRange, blockSize, cacheBlocks),
+	}
+}
+
+// ReadAt implements io.ReaderAt.
+func (s *ObjectStoreSource) ReadAt(p []byte, off int64) (int, error) { return s.cache.ReadAt(p, off) }
+
+// Size implements Source.
+func (s *ObjectStoreSource) Size() (int64, error) { return s.fetcher.Size() }
+
+// URI implements Source.
+func (s *ObjectStoreSource) URI() string { return s.uri }
+
+// blockCache serves ReadAt calls out of a fixed-size LRU cache of
+// fixed-size blocks, fetching missing blocks via fetch. It exists so that
+// remote sources (HTTP range requests, object store GETs) don't re-fetch
+// the same bytes on every Pos()/Seek()-heavy access a Kaitai parser makes.
+type blockCache struct {
+	mu        sync.Mutex
+	fetch     func(p []byte, off int64) (int, error)
+	blockSize int
+	maxBlocks int
+	blocks    map[int64][]byte
+	order     *list.List
+	elems     map[int64]*list.Element
+}
+
+func newBlockCache(fetch func(p []byte, off int64) (int, error), blockSize int, maxBlocks int) *blockCache {
+	if blockSize <= 0 {
+		blockSize = 64 * 1024
+	}
+	if maxBlocks <= 0 {
+		maxBlocks = 32
+	}
+	return &blockCache{
+		fetch:     fetch,
+		blockSize: blockSize,
+		maxBlocks: maxBlocks,
+		blocks:    map[int64][]byte{},
+		order:     list.New(),
+		elems:     map[int64]*list.Element{},
+	}
+}
+
+func (c *blockCache) getBlock(index int64) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if block, ok := c.blocks[index]; ok {
+		c.order.MoveToFront(c.elems[index])
+		return block, nil
+	}
+
+	buf := make([]byte, c.blockSize)
+	n, err := c.fetch(buf, index*int64(c.blockSize))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	buf = buf[:n]
+
+	c.blocks[index] = buf
+	c.elems[index] = c.order.PushFront(index)
+	if c.order.Len() > c.maxBlocks {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.blocks, oldest.Value.(int64))
+		delete(c.elems, oldest.Value.(int64))
+	}
+	return buf, nil
+}
+
+// ReadAt implements io.ReaderAt by serving p from one or more cached blocks.
+func (c *blockCache) ReadAt(p []byte, off int64) (int, error) {
+	total := 0
+	for total < len(p) {
+		index := (off + int64(total)) / int64(c.blockSize)
+		block, err := c.getBlock(index)
+		if err != nil {
+			return total, err
+		}
+
+		blockOff := int(off+int64(total)) % c.blockSize
+		if blockOff >= len(block) {
+			return total, io.EOF
+		}
+
+		n := copy(p[total:], block[blockOff:])
+		total += n
+		if n < c.blockSize-blockOff {
+			// Short block read means we hit EOF on the underlying source.
+			if total < len(p) {
+				return total, io.EOF
+			}
+		}
+	}
+	return total, nil
+}
+
+// sourceReadSeeker adapts a Source to io.ReadSeeker so it can be handed to
+// the existing NewStream constructor.
+type sourceReadSeeker struct {
+	src Source
+	pos int64
+}
+
+func (r *sourceReadSeeker) Read(p []byte) (int, error) {
+	n, err := r.src.ReadAt(p, r.pos)
+	r.pos += int64(n)
+	return n, err
+}
+
+func (r *sourceReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+		r.pos = offset
+	case io.SeekCurrent:
+		r.pos += offset
+	case io.SeekEnd:
+		// Only SeekEnd needs the source's size, so only it pays for a
+		// Source.Size() call — for HTTPRangeSource/ObjectStoreSource that
+		// means a network round trip, which the far more common
+		// SeekStart/SeekCurrent cases must not incur.
+		size, err := r.src.Size()
+		if err != nil {
+			return 0, err
+		}
+		r.pos = size + offset
+	default:
+		return 0, fmt.Errorf("kaitai: sourceReadSeeker.Seek: invalid whence %d", whence)
+	}
+	return r.pos, nil
+}
+
+// NewStreamFromSource creates a Stream reading from an arbitrary Source,
+// such as a remote HTTP range source or an S3/MinIO/GCS object store,
+// instead of requiring the whole input be local and fully buffered. Errors
+// surfaced while parsing the resulting Stream include the source's URI (see
+// locationInfo.msgWithLocation) when one is available.
+func NewStreamFromSource(src Source) *Stream {
+	s := NewStream(&sourceReadSeeker{src: src})
+	if uri := src.URI(); uri != "" {
+		sourceURIRegistryMu.Lock()
+		sourceURIRegistry[uintptr(unsafe.Pointer(s))] = uri
+		sourceURIRegistryMu.Unlock()
+		runtime.SetFinalizer(s, unregisterSourceURI)
+	}
+	return s
+}
+
+func unregisterSourceURI(s *Stream) {
+	sourceURIRegistryMu.Lock()
+	defer sourceURIRegistryMu.Unlock()
+	delete(sourceURIRegistry, uintptr(unsafe.Pointer(s)))
+}
@@ -0,0 +1,70 @@
+package kaitai
+
+import (
+	"io"
+	"testing"
+)
+
+func TestBytesSourceReadAt(t *testing.T) {
+	src := NewBytesSource([]byte("hello world"))
+
+	buf := make([]byte, 5)
+	if n, err := src.ReadAt(buf, 0); err != nil || n != 5 || string(buf) != "hello" {
+		t.Fatalf("unexpected read: n=%d err=%v buf=%q", n, err, buf)
+	}
+
+	buf = make([]byte, 5)
+	n, err := src.ReadAt(buf, 9)
+	if err != io.EOF {
+		t.Fatalf("expected io.EOF at the source boundary, got %v", err)
+	}
+	if n != 2 || string(buf[:n]) != "ld" {
+		t.Fatalf("unexpected short read at boundary: n=%d buf=%q", n, buf[:n])
+	}
+
+	if _, err := src.ReadAt(buf, 100); err == nil {
+		t.Fatal("expected an error reading past the end of the source")
+	}
+}
+
+func TestBlockCacheServesAcrossBlocksAndCachesBlocks(t *testing.T) {
+	data := []byte("0123456789ABCDEFGHIJ") // 20 bytes
+	src := NewBytesSource(data)
+
+	fetches := 0
+	fetch := func(p []byte, off int64) (int, error) {
+		fetches++
+		return src.ReadAt(p, off)
+	}
+	cache := newBlockCache(fetch, 8, 4)
+
+	buf := make([]byte, 10)
+	n, err := cache.ReadAt(buf, 5)
+	if err != nil {
+		t.Fatalf("unexpected error reading across blocks: %v", err)
+	}
+	if n != 10 || string(buf) != string(data[5:15]) {
+		t.Fatalf("unexpected read spanning blocks: n=%d buf=%q", n, buf)
+	}
+	if fetches != 2 {
+		t.Fatalf("expected a read spanning 2 blocks to fetch exactly twice, got %d", fetches)
+	}
+
+	// Re-reading the same range must be served from the cache, not refetched.
+	if n, err = cache.ReadAt(buf, 5); err != nil || n != 10 {
+		t.Fatalf("unexpected cached read: n=%d err=%v", n, err)
+	}
+	if fetches != 2 {
+		t.Fatalf("expected cached blocks to avoid refetching, got %d fetches", fetches)
+	}
+
+	// Reading up to the data boundary must return a short read plus io.EOF.
+	buf = make([]byte, 5)
+	n, err = cache.ReadAt(buf, 18)
+	if err != io.EOF {
+		t.Fatalf("expected io.EOF at the data boundary, got %v", err)
+	}
+	if n != 2 || string(buf[:n]) != string(data[18:20]) {
+		t.Fatalf("unexpected short read at boundary: n=%d buf=%q", n, buf[:n])
+	}
+}
@@ -0,0 +1,148 @@
+package kaitai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// ValidatorFunc is a user-supplied predicate backing a `valid: { custom: ... }`
+// check in a KSY spec. It returns a non-nil error if actual fails validation.
+type ValidatorFunc func(actual any, io *Stream, srcPath string) error
+
+// ValidatorCtxFunc is the context-aware variant of ValidatorFunc, for
+// validators that need to support cancellation or perform external I/O
+// (e.g. looking up a checksum against a remote service).
+type ValidatorCtxFunc func(ctx context.Context, actual any, io *Stream, srcPath string) error
+
+var (
+	validatorRegistryMu  sync.RWMutex
+	validatorRegistry    = map[string]ValidatorFunc{}
+	validatorCtxRegistry = map[string]ValidatorCtxFunc{}
+)
+
+// RegisterValidator registers a named custom validator for use by generated
+// code's `valid: { custom: name }` checks. Registering under a name that is
+// already taken overwrites the previous entry.
+func RegisterValidator(name string, fn ValidatorFunc) {
+	validatorRegistryMu.Lock()
+	defer validatorRegistryMu.Unlock()
+	validatorRegistry[name] = fn
+}
+
+// RegisterValidatorCtx registers a named context-aware custom validator, for
+// validators that need to support cancellation or perform external I/O.
+func RegisterValidatorCtx(name string, fn ValidatorCtxFunc) {
+	validatorRegistryMu.Lock()
+	defer validatorRegistryMu.Unlock()
+	validatorCtxRegistry[name] = fn
+}
+
+// lookupValidator returns the validator registered under name, if any.
+func lookupValidator(name string) (ValidatorFunc, bool) {
+	validatorRegistryMu.RLock()
+	defer validatorRegistryMu.RUnlock()
+	fn, ok := validatorRegistry[name]
+	return fn, ok
+}
+
+// lookupValidatorCtx returns the context-aware validator registered under
+// name, if any.
+func lookupValidatorCtx(name string) (ValidatorCtxFunc, bool) {
+	validatorRegistryMu.RLock()
+	defer validatorRegistryMu.RUnlock()
+	fn, ok := validatorCtxRegistry[name]
+	return fn, ok
+}
+
+// RunValidator looks up the validator registered under name and runs it
+// against actual, wrapping a failure as a ValidationCustomError. Generated
+// code calls this for `valid: { custom: name }` checks.
+func RunValidator(name string, actual any, io *Stream, srcPath string) error {
+	fn, ok := lookupValidator(name)
+	if !ok {
+		return fmt.Errorf("kaitai: no validator registered under name %q", name)
+	}
+	if err := fn(actual, io, srcPath); err != nil {
+		return NewValidationCustomError(name, actual, err, io, srcPath)
+	}
+	return nil
+}
+
+// RunValidatorCtx looks up the context-aware validator registered under
+// name and runs it against actual, wrapping a failure as a
+// ValidationCustomError. Generated code calls this for `valid: { custom:
+// name }` checks when parsing with a context.
+func RunValidatorCtx(ctx context.Context, name string, actual any, io *Stream, srcPath string) error {
+	fn, ok := lookupValidatorCtx(name)
+	if !ok {
+		if plainFn, plainOk := lookupValidator(name); plainOk {
+			fn = func(ctx context.Context, actual any, io *Stream, srcPath string) error {
+				return plainFn(actual, io, srcPath)
+			}
+		} else {
+			return fmt.Errorf("kaitai: no validator registered under name %q", name)
+		}
+	}
+	if err := fn(ctx, actual, io, srcPath); err != nil {
+		return NewValidationCustomError(name, actual, err, io, srcPath)
+	}
+	return nil
+}
+
+// ValidationCustomError signals validation failure: a user-registered
+// `valid: { custom: ... }` validator rejected "Actual". It wraps whatever
+// error the validator returned while still implementing ValidationFailedError.
+type ValidationCustomError struct {
+	locationInfo
+
+	name   string
+	actual any
+	err    error
+}
+
+// NewValidationCustomError creates a new ValidationCustomError instance.
+func NewValidationCustomError(
+	name string, actual any, err error, io *Stream, srcPath string) ValidationCustomError {
+	return ValidationCustomError{
+		newLocationInfo(io, srcPath),
+		name,
+		actual,
+		err,
+	}
+}
+
+// Name is a getter of the registered validator name that rejected the value.
+func (e ValidationCustomError) Name() string { return e.name }
+
+// Actual is a getter of the actual value associated with the validation error.
+func (e ValidationCustomError) Actual() any { return e.actual }
+
+// Unwrap returns the error returned by the custom validator, so that
+// errors.Is and errors.As can match against it.
+func (e ValidationCustomError) Unwrap() error { return e.err }
+
+func (e ValidationCustomError) Error() string {
+	return e.msgWithLocation(
+		validationFailedMsg(
+			fmt.Sprintf("custom validator %q rejected value %v: %s", e.name, e.actual, e.err),
+		),
+	)
+}
+
+// ErrorKind returns the stable, machine-readable identifier for this error,
+// suitable for JSON serialization and use by external tooling.
+func (ValidationCustomError) ErrorKind() string { return "custom" }
+
+// MarshalJSON implements json.Marshaler, emitting a canonical JSON
+// representation of the error for tooling that wraps Kaitai-generated
+// parsers (linters, dissectors, fuzzers, CI reporters).
+func (e ValidationCustomError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonError{
+		Kind:    e.ErrorKind(),
+		SrcPath: e.SrcPath(),
+		Pos:     posOf(e.Io()),
+		Actual:  e.actual,
+	})
+}
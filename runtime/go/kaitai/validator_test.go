@@ -0,0 +1,92 @@
+package kaitai
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestRunValidatorSuccessAndFailure(t *testing.T) {
+	RegisterValidator("test_even", func(actual any, io *Stream, srcPath string) error {
+		if actual.(int)%2 != 0 {
+			return errors.New("not even")
+		}
+		return nil
+	})
+
+	if err := RunValidator("test_even", 4, nil, "field_a"); err != nil {
+		t.Errorf("expected a passing validator to return nil, got %v", err)
+	}
+
+	err := RunValidator("test_even", 3, nil, "field_a")
+	var custom ValidationCustomError
+	if !errors.As(err, &custom) {
+		t.Fatalf("expected a ValidationCustomError, got %T: %v", err, err)
+	}
+	if custom.Name() != "test_even" {
+		t.Errorf(`expected name "test_even", got %q`, custom.Name())
+	}
+	if custom.Actual() != 3 {
+		t.Errorf("expected actual=3, got %v", custom.Actual())
+	}
+}
+
+func TestRunValidatorUnregisteredName(t *testing.T) {
+	if err := RunValidator("test_does_not_exist", 1, nil, "field_a"); err == nil {
+		t.Error("expected an error for an unregistered validator name")
+	}
+}
+
+func TestRunValidatorCtxFallsBackToPlainValidator(t *testing.T) {
+	RegisterValidator("test_ctx_fallback", func(actual any, io *Stream, srcPath string) error {
+		if actual != "ok" {
+			return errors.New("not ok")
+		}
+		return nil
+	})
+
+	if err := RunValidatorCtx(context.Background(), "test_ctx_fallback", "ok", nil, "field_b"); err != nil {
+		t.Errorf("expected the plain validator fallback to pass, got %v", err)
+	}
+
+	err := RunValidatorCtx(context.Background(), "test_ctx_fallback", "bad", nil, "field_b")
+	var custom ValidationCustomError
+	if !errors.As(err, &custom) {
+		t.Fatalf("expected a ValidationCustomError from the fallback path, got %T: %v", err, err)
+	}
+}
+
+func TestRunValidatorCtxPrefersRegisteredCtxValidator(t *testing.T) {
+	called := false
+	RegisterValidator("test_ctx_native", func(actual any, io *Stream, srcPath string) error {
+		return errors.New("plain validator must not run when a ctx validator is registered")
+	})
+	RegisterValidatorCtx("test_ctx_native", func(ctx context.Context, actual any, io *Stream, srcPath string) error {
+		called = true
+		return nil
+	})
+
+	if err := RunValidatorCtx(context.Background(), "test_ctx_native", "x", nil, "field_c"); err != nil {
+		t.Errorf("expected the ctx-native validator to pass, got %v", err)
+	}
+	if !called {
+		t.Error("expected the registered ValidatorCtxFunc to be invoked")
+	}
+}
+
+func TestValidatorRegistryIsSafeForConcurrentRegisterAndLookup(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			RegisterValidator("test_concurrent", func(actual any, io *Stream, srcPath string) error { return nil })
+		}()
+		go func() {
+			defer wg.Done()
+			_ = RunValidator("test_concurrent", 1, nil, "field_d")
+		}()
+	}
+	wg.Wait()
+}